@@ -0,0 +1,128 @@
+// Package cache provides an on-disk store for scraped chapter HTML, so
+// repeat runs only re-fetch chapters that have actually changed.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Entry records what we know about a previously-fetched URL.
+type Entry struct {
+	Slug         string `json:"slug"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	DatePosted   string `json:"date_posted,omitempty"`
+}
+
+// Store is a directory of cached chapter HTML (one file per chapter, named
+// by slug) plus a JSON index keyed by URL.
+type Store struct {
+	dir       string
+	indexPath string
+
+	mu    sync.Mutex
+	index map[string]Entry
+}
+
+// Open loads (or creates) the cache directory and its index at dir.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:       dir,
+		indexPath: filepath.Join(dir, "index.json"),
+		index:     map[string]Entry{},
+	}
+
+	data, err := os.ReadFile(s.indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.index); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Entry returns the stored ETag/Last-Modified/date for url, if any, along
+// with the cached HTML body.
+func (s *Store) Entry(url string) (Entry, string, bool) {
+	s.mu.Lock()
+	entry, ok := s.index[url]
+	s.mu.Unlock()
+	if !ok {
+		return Entry{}, "", false
+	}
+
+	body, err := os.ReadFile(filepath.Join(s.dir, entry.Slug+".html"))
+	if err != nil {
+		return Entry{}, "", false
+	}
+	return entry, string(body), true
+}
+
+// Save writes the chapter body to disk and records its validators so a
+// future run can issue a conditional GET.
+func (s *Store) Save(url, body, etag, lastModified, datePosted string) error {
+	slug := Slugify(url)
+
+	if err := os.WriteFile(filepath.Join(s.dir, slug+".html"), []byte(body), 0644); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index[url] = Entry{
+		Slug:         slug,
+		ETag:         etag,
+		LastModified: lastModified,
+		DatePosted:   datePosted,
+	}
+	return s.writeIndexLocked()
+}
+
+// SetDatePosted updates the recorded chapter date for an already-cached
+// URL, without touching its body or validators.
+func (s *Store) SetDatePosted(url, datePosted string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.index[url]
+	if !ok {
+		return nil
+	}
+	entry.DatePosted = datePosted
+	s.index[url] = entry
+	return s.writeIndexLocked()
+}
+
+// writeIndexLocked marshals and writes the index to disk. Callers must hold
+// s.mu for the duration of the write, so that two goroutines' index updates
+// can never land on disk out of order.
+func (s *Store) writeIndexLocked() error {
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.indexPath, data, 0644)
+}
+
+var slugRe = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// Slugify turns a chapter URL into a filesystem-safe cache key.
+func Slugify(url string) string {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(url, "https://"), "/")
+	trimmed = strings.TrimPrefix(trimmed, "http://")
+	return strings.Trim(slugRe.ReplaceAllString(trimmed, "-"), "-")
+}
@@ -0,0 +1,40 @@
+package cache
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "strips https scheme and trailing slash",
+			url:  "https://parahumans.wordpress.com/2011/06/11/1-1/",
+			want: "parahumans-wordpress-com-2011-06-11-1-1",
+		},
+		{
+			name: "strips http scheme",
+			url:  "http://parahumans.wordpress.com/1-1",
+			want: "parahumans-wordpress-com-1-1",
+		},
+		{
+			name: "collapses runs of non-alphanumerics",
+			url:  "https://example.com/a--b__c",
+			want: "example-com-a-b-c",
+		},
+		{
+			name: "trims leading and trailing separators",
+			url:  "https://",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.url); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
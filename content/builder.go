@@ -0,0 +1,105 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// BuildBlocks walks the direct children of an entry-content selection and
+// turns each <p>/<blockquote> into a Block, skipping pure-navigation
+// paragraphs (e.g. "Previous Chapter / Next Chapter" links).
+func BuildBlocks(entryContent *goquery.Selection) []Block {
+	var blocks []Block
+
+	entryContent.Children().Each(func(_ int, node *goquery.Selection) {
+		switch goquery.NodeName(node) {
+		case "p":
+			if isNavParagraph(node) {
+				return
+			}
+			blocks = append(blocks, paragraphBlock(node))
+		case "blockquote":
+			blocks = append(blocks, Blockquote{Children: BuildBlocks(node)})
+		}
+	})
+
+	return blocks
+}
+
+// paragraphBlock turns a single <p> into a Paragraph, Indented, or
+// Separator block depending on the formatting attributes WordPress left on
+// it.
+func paragraphBlock(p *goquery.Selection) Block {
+	if val, exists := p.Attr("text-align"); exists && val == "center" {
+		return Separator{}
+	}
+
+	inline := buildInlines(p)
+	if val, exists := p.Attr("padding-left"); exists && val == "30px" {
+		return Indented{Children: []Block{Paragraph{Children: inline}}}
+	}
+	return Paragraph{Children: inline}
+}
+
+// isNavParagraph reports whether p's only content is links, i.e. it's one
+// of the "Previous Chapter"/"Next Chapter" navigation paragraphs rather
+// than real chapter text.
+func isNavParagraph(p *goquery.Selection) bool {
+	if p.Find("a").Length() == 0 {
+		return false
+	}
+	clone := p.Clone()
+	clone.Find("a").Remove()
+	return strings.TrimSpace(clone.Text()) == ""
+}
+
+// buildInlines walks sel's child nodes (text and inline elements) into an
+// Inline tree.
+func buildInlines(sel *goquery.Selection) []Inline {
+	var out []Inline
+
+	sel.Contents().Each(func(_ int, c *goquery.Selection) {
+		node := c.Get(0)
+		if node == nil {
+			return
+		}
+
+		switch node.Type {
+		case html.TextNode:
+			if text := cleanText(node.Data); text != "" {
+				out = append(out, Text(text))
+			}
+		case html.ElementNode:
+			switch node.Data {
+			case "em", "i":
+				out = append(out, Emph{Children: buildInlines(c)})
+			case "strong", "b":
+				out = append(out, Strong{Children: buildInlines(c)})
+			case "a":
+				href, _ := c.Attr("href")
+				out = append(out, Link{Href: href, Children: buildInlines(c)})
+			case "br":
+				out = append(out, LineBreak{})
+			case "img":
+				src, _ := c.Attr("src")
+				alt, _ := c.Attr("alt")
+				out = append(out, Image{Src: src, Alt: alt})
+			default:
+				out = append(out, buildInlines(c)...)
+			}
+		}
+	})
+
+	return out
+}
+
+// cleanText mirrors the old Paragraph.Format whitespace cleanup: the
+// source HTML wraps its paragraph text across lines, and those newlines
+// aren't meaningful.
+func cleanText(s string) string {
+	s = strings.Replace(s, "\n", "", -1)
+	s = strings.Replace(s, ".  ", ". ", -1)
+	return s
+}
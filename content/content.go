@@ -0,0 +1,83 @@
+// Package content is a typed model of a chapter's body, built by walking a
+// chapter's HTML once and rendered out again per output format. This is
+// what lets --with-link and friends, and non-Markdown output formats,
+// render links, blockquotes, and nested emphasis properly instead of
+// losing them to ad hoc string replacement.
+package content
+
+// Inline is one of Text, Emph, Strong, Link, Image, or LineBreak.
+type Inline interface {
+	isInline()
+}
+
+// Text is a run of plain text.
+type Text string
+
+func (Text) isInline() {}
+
+// Emph is emphasized (<em>/<i>) text.
+type Emph struct {
+	Children []Inline
+}
+
+func (Emph) isInline() {}
+
+// Strong is bold (<strong>/<b>) text.
+type Strong struct {
+	Children []Inline
+}
+
+func (Strong) isInline() {}
+
+// Link is a hyperlink, e.g. a footnote reference.
+type Link struct {
+	Href     string
+	Children []Inline
+}
+
+func (Link) isInline() {}
+
+// Image is an inline image.
+type Image struct {
+	Src string
+	Alt string
+}
+
+func (Image) isInline() {}
+
+// LineBreak is an explicit <br> within a block.
+type LineBreak struct{}
+
+func (LineBreak) isInline() {}
+
+// Block is one of Paragraph, Blockquote, Separator, or Indented.
+type Block interface {
+	isBlock()
+}
+
+// Paragraph is an ordinary paragraph of Inline content.
+type Paragraph struct {
+	Children []Inline
+}
+
+func (Paragraph) isBlock() {}
+
+// Blockquote is a quoted block, made up of nested Blocks.
+type Blockquote struct {
+	Children []Block
+}
+
+func (Blockquote) isBlock() {}
+
+// Separator is a scene break (e.g. a centered "* * *" paragraph).
+type Separator struct{}
+
+func (Separator) isBlock() {}
+
+// Indented is a block quoted with a left-padding indent rather than a
+// proper <blockquote> - a formatting quirk of the source WordPress themes.
+type Indented struct {
+	Children []Block
+}
+
+func (Indented) isBlock() {}
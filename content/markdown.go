@@ -0,0 +1,62 @@
+package content
+
+import "strings"
+
+// RenderMarkdown renders blocks as Markdown, the format worm_scraper has
+// always produced for --format md.
+func RenderMarkdown(blocks []Block) string {
+	var b strings.Builder
+	writeBlocksMarkdown(&b, blocks, 0)
+	return b.String()
+}
+
+func writeBlocksMarkdown(b *strings.Builder, blocks []Block, quoteDepth int) {
+	for i, blk := range blocks {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		writeBlockMarkdown(b, blk, quoteDepth)
+	}
+}
+
+func writeBlockMarkdown(b *strings.Builder, blk Block, quoteDepth int) {
+	prefix := strings.Repeat("> ", quoteDepth)
+
+	switch v := blk.(type) {
+	case Paragraph:
+		b.WriteString(prefix)
+		writeInlineMarkdown(b, v.Children)
+	case Indented:
+		b.WriteString(prefix + "    ")
+		writeBlocksMarkdown(b, v.Children, quoteDepth)
+	case Blockquote:
+		writeBlocksMarkdown(b, v.Children, quoteDepth+1)
+	case Separator:
+		b.WriteString(prefix + "----------")
+	}
+}
+
+func writeInlineMarkdown(b *strings.Builder, inlines []Inline) {
+	for _, in := range inlines {
+		switch v := in.(type) {
+		case Text:
+			b.WriteString(string(v))
+		case Emph:
+			b.WriteString("*")
+			writeInlineMarkdown(b, v.Children)
+			b.WriteString("*")
+		case Strong:
+			b.WriteString("**")
+			writeInlineMarkdown(b, v.Children)
+			b.WriteString("**")
+		case Link:
+			b.WriteString("[")
+			writeInlineMarkdown(b, v.Children)
+			b.WriteString("](" + v.Href + ")")
+		case Image:
+			b.WriteString("![" + v.Alt + "](" + v.Src + ")")
+		case LineBreak:
+			b.WriteString("  \n")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package content
+
+import "strings"
+
+// RenderPlainText renders blocks as plain text, dropping all markup and
+// flattening links down to just their visible text.
+func RenderPlainText(blocks []Block) string {
+	var b strings.Builder
+	for i, blk := range blocks {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		writeBlockPlainText(&b, blk, 0)
+	}
+	return b.String()
+}
+
+func writeBlockPlainText(b *strings.Builder, blk Block, indent int) {
+	switch v := blk.(type) {
+	case Paragraph:
+		b.WriteString(strings.Repeat("    ", indent))
+		writeInlinePlainText(b, v.Children)
+	case Indented:
+		for i, child := range v.Children {
+			if i > 0 {
+				b.WriteString("\n\n")
+			}
+			writeBlockPlainText(b, child, indent+1)
+		}
+	case Blockquote:
+		for i, child := range v.Children {
+			if i > 0 {
+				b.WriteString("\n\n")
+			}
+			writeBlockPlainText(b, child, indent+1)
+		}
+	case Separator:
+		b.WriteString("* * *")
+	}
+}
+
+func writeInlinePlainText(b *strings.Builder, inlines []Inline) {
+	for _, in := range inlines {
+		switch v := in.(type) {
+		case Text:
+			b.WriteString(string(v))
+		case Emph:
+			writeInlinePlainText(b, v.Children)
+		case Strong:
+			writeInlinePlainText(b, v.Children)
+		case Link:
+			writeInlinePlainText(b, v.Children)
+		case Image:
+			b.WriteString(v.Alt)
+		case LineBreak:
+			b.WriteString("\n")
+		}
+	}
+}
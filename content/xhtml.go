@@ -0,0 +1,65 @@
+package content
+
+import (
+	"html"
+	"strings"
+)
+
+// RenderXHTML renders blocks as the XHTML fragment go-epub and the MOBI
+// bundle embed directly into a chapter's body.
+func RenderXHTML(blocks []Block) string {
+	var b strings.Builder
+	for _, blk := range blocks {
+		writeBlockXHTML(&b, blk)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func writeBlockXHTML(b *strings.Builder, blk Block) {
+	switch v := blk.(type) {
+	case Paragraph:
+		b.WriteString("<p>")
+		writeInlineXHTML(b, v.Children)
+		b.WriteString("</p>")
+	case Indented:
+		b.WriteString(`<div class="indented">`)
+		for _, child := range v.Children {
+			writeBlockXHTML(b, child)
+		}
+		b.WriteString("</div>")
+	case Blockquote:
+		b.WriteString("<blockquote>")
+		for _, child := range v.Children {
+			writeBlockXHTML(b, child)
+		}
+		b.WriteString("</blockquote>")
+	case Separator:
+		b.WriteString(`<p class="separator">***</p>`)
+	}
+}
+
+func writeInlineXHTML(b *strings.Builder, inlines []Inline) {
+	for _, in := range inlines {
+		switch v := in.(type) {
+		case Text:
+			b.WriteString(html.EscapeString(string(v)))
+		case Emph:
+			b.WriteString("<em>")
+			writeInlineXHTML(b, v.Children)
+			b.WriteString("</em>")
+		case Strong:
+			b.WriteString("<strong>")
+			writeInlineXHTML(b, v.Children)
+			b.WriteString("</strong>")
+		case Link:
+			b.WriteString(`<a href="` + html.EscapeString(v.Href) + `">`)
+			writeInlineXHTML(b, v.Children)
+			b.WriteString("</a>")
+		case Image:
+			b.WriteString(`<img src="` + html.EscapeString(v.Src) + `" alt="` + html.EscapeString(v.Alt) + `"/>`)
+		case LineBreak:
+			b.WriteString("<br/>")
+		}
+	}
+}
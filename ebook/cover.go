@@ -0,0 +1,49 @@
+package ebook
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// coverWidth and coverHeight match a typical EPUB cover's 2:3 aspect ratio.
+const (
+	coverWidth  = 1200
+	coverHeight = 1800
+)
+
+// generateCover renders a plain solid-color title-page image, derived
+// deterministically from title so different books get different covers,
+// and encodes it as PNG. There's no font rendering in the standard
+// library, so this is a placeholder cover rather than a true title page.
+func generateCover(title string) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, coverWidth, coverHeight))
+	bg := coverColor(title)
+	for y := 0; y < coverHeight; y++ {
+		for x := 0; x < coverWidth; x++ {
+			img.Set(x, y, bg)
+		}
+	}
+
+	var buf bytes.Buffer
+	// png.Encode only fails if the writer returns an error, which
+	// bytes.Buffer never does.
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// coverColor picks a muted color from title so repeated runs for the same
+// book produce the same cover.
+func coverColor(title string) color.RGBA {
+	var hash uint32
+	for i := 0; i < len(title); i++ {
+		hash = hash*31 + uint32(title[i])
+	}
+	return color.RGBA{
+		R: uint8(60 + hash%120),
+		G: uint8(60 + (hash>>8)%120),
+		B: uint8(60 + (hash>>16)%120),
+		A: 255,
+	}
+}
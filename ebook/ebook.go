@@ -0,0 +1,36 @@
+// Package ebook turns the scraped Worm data into on-disk ebook formats
+// (EPUB, MOBI) without shelling out to Pandoc.
+package ebook
+
+import "github.com/Vanathor/worm_scraper/content"
+
+// Chapter is a single chapter's worth of renderable content.
+type Chapter struct {
+	Title      string
+	Url        string
+	Tags       []string
+	DatePosted string
+	Blocks     []content.Block
+}
+
+// Arc is a titled grouping of Chapters, matching the serial's table of
+// contents structure.
+type Arc struct {
+	Identifier string
+	Title      string
+	Chapters   []Chapter
+}
+
+// Options controls the metadata lines rendered under each chapter heading.
+type Options struct {
+	WithLink bool
+	WithTags bool
+	WithDate bool
+}
+
+// Metadata describes the book as a whole, independent of format.
+type Metadata struct {
+	Title       string
+	Author      string
+	Description string
+}
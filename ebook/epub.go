@@ -0,0 +1,90 @@
+package ebook
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+
+	epub "github.com/go-shiori/go-epub"
+
+	"github.com/Vanathor/worm_scraper/content"
+)
+
+// css is embedded directly so the generated EPUB is self-contained.
+const css = `
+body { font-family: serif; }
+p { margin: 0 0 1em 0; text-indent: 1.5em; }
+.indented { margin-left: 2em; }
+.indented p { text-indent: 0; }
+p.separator { text-align: center; text-indent: 0; }
+blockquote { margin: 1em 2em; font-style: italic; }
+.chapter-meta { font-style: italic; font-size: 0.9em; }
+`
+
+// WriteEPUB builds an EPUB 3 file from arcs: one cover/title page, a
+// chapter per Chapter, and a CSS stylesheet, then writes it to path.
+func WriteEPUB(meta Metadata, arcs []Arc, opts Options, path string) error {
+	e, err := epub.NewEpub(meta.Title)
+	if err != nil {
+		return fmt.Errorf("creating epub: %w", err)
+	}
+	e.SetAuthor(meta.Author)
+	if meta.Description != "" {
+		e.SetDescription(meta.Description)
+	}
+
+	coverDataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(generateCover(meta.Title))
+	coverPath, err := e.AddImage(coverDataURL, "cover.png")
+	if err != nil {
+		return fmt.Errorf("adding cover: %w", err)
+	}
+	if err := e.SetCover(coverPath, ""); err != nil {
+		return fmt.Errorf("setting cover: %w", err)
+	}
+
+	// AddCSS treats its source argument as a file path, URL, or data URL to
+	// fetch rather than raw CSS text, so the embedded stylesheet has to be
+	// handed over as a data URL instead of being passed inline.
+	cssDataURL := "data:text/css;base64," + base64.StdEncoding.EncodeToString([]byte(css))
+	cssPath, err := e.AddCSS(cssDataURL, "")
+	if err != nil {
+		return fmt.Errorf("adding stylesheet: %w", err)
+	}
+
+	for _, arc := range arcs {
+		for _, ch := range arc.Chapters {
+			body := chapterXHTML(ch, opts)
+			if _, err := e.AddSection(body, ch.Title, "", cssPath); err != nil {
+				return fmt.Errorf("adding chapter %q: %w", ch.Title, err)
+			}
+		}
+	}
+
+	if err := e.Write(path); err != nil {
+		return fmt.Errorf("writing epub: %w", err)
+	}
+	return nil
+}
+
+// chapterXHTML renders a single chapter's body as the inline XHTML that
+// go-epub expects inside a section.
+func chapterXHTML(ch Chapter, opts Options) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(ch.Title))
+
+	if opts.WithTags && len(ch.Tags) > 0 {
+		fmt.Fprintf(&b, "<p class=\"chapter-meta\">Tags: %s</p>\n", html.EscapeString(strings.Join(ch.Tags, ", ")))
+	}
+	if opts.WithDate && ch.DatePosted != "" {
+		fmt.Fprintf(&b, "<p class=\"chapter-meta\">Posted: %s</p>\n", html.EscapeString(ch.DatePosted))
+	}
+	if opts.WithLink && ch.Url != "" {
+		fmt.Fprintf(&b, "<p class=\"chapter-meta\">Source: <a href=\"%s\">%s</a></p>\n", html.EscapeString(ch.Url), html.EscapeString(ch.Url))
+	}
+
+	b.WriteString(content.RenderXHTML(ch.Blocks))
+
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package ebook
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Vanathor/worm_scraper/content"
+)
+
+// mobi OPF/HTML templates, following the content.html + toc.ncx + nav.html
+// bundle layout kindlegen expects (see missdeer's epub2mobi gist).
+const contentTemplate = `<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>{{.Meta.Title}}</title></head>
+<body>
+<h1>{{.Meta.Title}}</h1>
+<h2>{{.Meta.Author}}</h2>
+{{range .Arcs}}
+<h2 id="arc-{{.Identifier}}">{{.Title}}</h2>
+{{range .Chapters}}
+<h3 id="{{.ID}}">{{.Title}}</h3>
+{{.Body}}
+{{end}}
+{{end}}
+</body>
+</html>
+`
+
+const ncxTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+<meta name="dtb:uid" content="{{.Meta.Title}}"/>
+<meta name="dtb:depth" content="1"/>
+</head>
+<docTitle><text>{{.Meta.Title}}</text></docTitle>
+<navMap>
+{{range .Chapters}}<navPoint id="navpoint-{{.Order}}" playOrder="{{.Order}}">
+<navLabel><text>{{.Title}}</text></navLabel>
+<content src="content.html#{{.ID}}"/>
+</navPoint>
+{{end}}
+</navMap>
+</ncx>
+`
+
+const navTemplate = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>Nav</title></head>
+<body>
+<nav epub:type="toc">
+<ol>
+{{range .Chapters}}<li><a href="content.html#{{.ID}}">{{.Title}}</a></li>
+{{end}}
+</ol>
+</nav>
+</body>
+</html>
+`
+
+const opfTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" unique-identifier="uid" version="2.0">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title>{{.Meta.Title}}</dc:title>
+<dc:creator>{{.Meta.Author}}</dc:creator>
+<dc:language>en</dc:language>
+<dc:identifier id="uid">{{.Meta.Title}}</dc:identifier>
+</metadata>
+<manifest>
+<item id="content" href="content.html" media-type="application/xhtml+xml"/>
+<item id="toc" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+<item id="nav" href="nav.html" media-type="application/xhtml+xml" properties="nav"/>
+</manifest>
+<spine toc="toc">
+<itemref idref="content"/>
+</spine>
+</package>
+`
+
+// mobiChapter flattens a Chapter down to what the templates need, plus the
+// unique ID and playOrder kindlegen wants in the NCX.
+type mobiChapter struct {
+	ID    string
+	Order int
+	Title string
+	Body  string
+}
+
+type mobiArc struct {
+	Identifier string
+	Title      string
+	Chapters   []mobiChapter
+}
+
+type mobiData struct {
+	Meta     Metadata
+	Arcs     []mobiArc
+	Chapters []mobiChapter // flattened, for the NCX/nav chapter list
+}
+
+// WriteMOBI assembles the kindlegen-style content.html + toc.ncx + nav.html
+// + OPF bundle in a temp directory and, if kindlegen is on PATH, compiles it
+// to a .mobi at path. If kindlegen isn't found, the bundle is left in place
+// next to path so it can be compiled manually.
+func WriteMOBI(meta Metadata, arcs []Arc, path string) error {
+	data := buildMobiData(meta, arcs)
+
+	bundleDir := strings.TrimSuffix(path, filepath.Ext(path)) + "-mobi-bundle"
+	if err := os.MkdirAll(bundleDir, 0755); err != nil {
+		return fmt.Errorf("creating mobi bundle dir: %w", err)
+	}
+
+	files := map[string]string{
+		"content.html": contentTemplate,
+		"toc.ncx":      ncxTemplate,
+		"nav.html":     navTemplate,
+		"book.opf":     opfTemplate,
+	}
+	for name, tmpl := range files {
+		if err := renderTemplate(filepath.Join(bundleDir, name), name, tmpl, data); err != nil {
+			return err
+		}
+	}
+
+	kindlegen, err := exec.LookPath("kindlegen")
+	if err != nil {
+		fmt.Printf("kindlegen not found on PATH; left the MOBI bundle at %s for manual conversion\n", bundleDir)
+		return nil
+	}
+
+	cmd := exec.Command(kindlegen, "book.opf", "-o", filepath.Base(path))
+	cmd.Dir = bundleDir
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("kindlegen failed: %w", err)
+	}
+
+	if err := os.Rename(filepath.Join(bundleDir, filepath.Base(path)), path); err != nil {
+		return fmt.Errorf("moving generated mobi into place: %w", err)
+	}
+	return os.RemoveAll(bundleDir)
+}
+
+// buildMobiData flattens arcs into the templates' data, escaping every
+// title/author field along the way since contentTemplate/ncxTemplate/
+// navTemplate/opfTemplate are parsed with text/template (not html/template)
+// into strict XML/XHTML documents. ch.Body is exempt because
+// content.RenderXHTML already escapes it.
+func buildMobiData(meta Metadata, arcs []Arc) mobiData {
+	meta.Title = html.EscapeString(meta.Title)
+	meta.Author = html.EscapeString(meta.Author)
+
+	data := mobiData{Meta: meta}
+	order := 1
+	for _, arc := range arcs {
+		mArc := mobiArc{Identifier: arc.Identifier, Title: html.EscapeString(arc.Title)}
+		for _, ch := range arc.Chapters {
+			mCh := mobiChapter{
+				ID:    fmt.Sprintf("chapter-%d", order),
+				Order: order,
+				Title: html.EscapeString(ch.Title),
+				Body:  content.RenderXHTML(ch.Blocks),
+			}
+			mArc.Chapters = append(mArc.Chapters, mCh)
+			data.Chapters = append(data.Chapters, mCh)
+			order++
+		}
+		data.Arcs = append(data.Arcs, mArc)
+	}
+	return data
+}
+
+func renderTemplate(path, name, text string, data mobiData) error {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+	defer f.Close()
+	if err := t.Execute(f, data); err != nil {
+		return fmt.Errorf("rendering %s: %w", name, err)
+	}
+	return nil
+}
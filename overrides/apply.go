@@ -0,0 +1,96 @@
+package overrides
+
+import "github.com/Vanathor/worm_scraper/serial"
+
+// Apply performs each of so's chapter edits against arcs in order, then
+// returns arcs. Edits that can't be resolved (an unknown After title, a
+// Title that belongs to no arc) are skipped rather than treated as fatal,
+// since a stale override shouldn't take down a whole scrape.
+func Apply(arcs []*serial.Arc, so SerialOverrides) []*serial.Arc {
+	for _, co := range so.Chapters {
+		switch co.Op {
+		case "insert":
+			insertChapter(arcs, co)
+		case "remove":
+			removeChapter(arcs, co)
+		case "rename":
+			renameChapter(arcs, co)
+		case "move":
+			moveChapter(arcs, co)
+		}
+	}
+	return arcs
+}
+
+// findChapter returns the arc and index of the chapter titled title,
+// searching every arc in order.
+func findChapter(arcs []*serial.Arc, title string) (arc *serial.Arc, index int) {
+	for _, arc := range arcs {
+		for i, ch := range arc.Chapters {
+			if ch.Title == title {
+				return arc, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// insertAt splices ch into chapters right after the chapter titled after,
+// or at the front if after is empty or not found.
+func insertAt(chapters []serial.Chapter, ch serial.Chapter, after string) []serial.Chapter {
+	at := 0
+	if after != "" {
+		for i, existing := range chapters {
+			if existing.Title == after {
+				at = i + 1
+				break
+			}
+		}
+	}
+	chapters = append(chapters, serial.Chapter{})
+	copy(chapters[at+1:], chapters[at:])
+	chapters[at] = ch
+	return chapters
+}
+
+func insertChapter(arcs []*serial.Arc, co ChapterOverride) {
+	// WhichArc slices the first two characters of the title to find its
+	// arc identifier; a title that's too short to have one just can't be
+	// placed, so skip it like any other unresolvable override.
+	if len(co.Title) < 2 {
+		return
+	}
+
+	ch := serial.Chapter{Title: co.Title, Url: co.Url}
+	arc, err := ch.WhichArc(arcs)
+	if err != nil {
+		return
+	}
+	arc.Chapters = insertAt(arc.Chapters, ch, co.After)
+}
+
+func removeChapter(arcs []*serial.Arc, co ChapterOverride) {
+	arc, i := findChapter(arcs, co.Title)
+	if arc == nil {
+		return
+	}
+	arc.Chapters = append(arc.Chapters[:i], arc.Chapters[i+1:]...)
+}
+
+func renameChapter(arcs []*serial.Arc, co ChapterOverride) {
+	arc, i := findChapter(arcs, co.Title)
+	if arc == nil {
+		return
+	}
+	arc.Chapters[i].Title = co.RenameTo
+}
+
+func moveChapter(arcs []*serial.Arc, co ChapterOverride) {
+	arc, i := findChapter(arcs, co.Title)
+	if arc == nil {
+		return
+	}
+	ch := arc.Chapters[i]
+	arc.Chapters = append(arc.Chapters[:i], arc.Chapters[i+1:]...)
+	arc.Chapters = insertAt(arc.Chapters, ch, co.After)
+}
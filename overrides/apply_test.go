@@ -0,0 +1,91 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/Vanathor/worm_scraper/serial"
+)
+
+func arcs(titles ...string) []*serial.Arc {
+	var out []*serial.Arc
+	for _, title := range titles {
+		out = append(out, &serial.Arc{
+			Identifier: title,
+			Chapters: []serial.Chapter{
+				{Title: title + ".1"},
+				{Title: title + ".2"},
+			},
+		})
+	}
+	return out
+}
+
+func titlesOf(arcList []*serial.Arc) []string {
+	var out []string
+	for _, arc := range arcList {
+		for _, ch := range arc.Chapters {
+			out = append(out, ch.Title)
+		}
+	}
+	return out
+}
+
+func TestApplyInsert(t *testing.T) {
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "insert", Title: "1.a", After: "1.1"},
+	}})
+	want := []string{"1.1", "1.a", "1.2"}
+	assertTitles(t, got, want)
+}
+
+func TestApplyInsertSkipsShortTitle(t *testing.T) {
+	// Regression: an insert whose title is too short to carry an arc
+	// identifier must be skipped rather than panicking in WhichArc.
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "insert", Title: "x"},
+	}})
+	assertTitles(t, got, []string{"1.1", "1.2"})
+}
+
+func TestApplyRemove(t *testing.T) {
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "remove", Title: "1.1"},
+	}})
+	assertTitles(t, got, []string{"1.2"})
+}
+
+func TestApplyRename(t *testing.T) {
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "rename", Title: "1.1", RenameTo: "1.renamed"},
+	}})
+	assertTitles(t, got, []string{"1.renamed", "1.2"})
+}
+
+func TestApplyMove(t *testing.T) {
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "move", Title: "1.1", After: "1.2"},
+	}})
+	assertTitles(t, got, []string{"1.2", "1.1"})
+}
+
+func TestApplyUnresolvableOverridesAreSkipped(t *testing.T) {
+	got := Apply(arcs("1"), SerialOverrides{Chapters: []ChapterOverride{
+		{Op: "remove", Title: "no-such-chapter"},
+		{Op: "rename", Title: "no-such-chapter", RenameTo: "x"},
+		{Op: "move", Title: "no-such-chapter"},
+	}})
+	assertTitles(t, got, []string{"1.1", "1.2"})
+}
+
+func assertTitles(t *testing.T, got []*serial.Arc, want []string) {
+	t.Helper()
+	gotTitles := titlesOf(got)
+	if len(gotTitles) != len(want) {
+		t.Fatalf("titles = %v, want %v", gotTitles, want)
+	}
+	for i := range want {
+		if gotTitles[i] != want[i] {
+			t.Fatalf("titles = %v, want %v", gotTitles, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package overrides lets a TOC's quirks be patched from a config file
+// instead of code: inserting, removing, renaming, or reordering chapters
+// within an arc, and swapping in a different arc-identifier regex.
+package overrides
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtinYAML ships the fixups worm_scraper has always needed out of the
+// box (currently just Worm's missing E.2 link), so a fresh checkout works
+// without an overrides.yaml of its own.
+//
+//go:embed builtin.yaml
+var builtinYAML []byte
+
+// Config holds the per-serial overrides parsed from an overrides file.
+type Config struct {
+	Serials map[string]SerialOverrides `yaml:"serials"`
+}
+
+// SerialOverrides is everything that can be patched for one serial.
+type SerialOverrides struct {
+	ArcIdentifierRegex string            `yaml:"arc_identifier_regex,omitempty"`
+	Chapters           []ChapterOverride `yaml:"chapters,omitempty"`
+}
+
+// ChapterOverride describes a single edit to make to a serial's table of
+// contents, applied in the order they're listed.
+//
+// Op is one of:
+//   - "insert": add a new chapter titled Title at Url, placed after the
+//     chapter titled After (or at the front of its arc if After is empty).
+//     The arc is whichever one Title's prefix belongs to, same as a real
+//     scraped chapter (see serial.Chapter.WhichArc).
+//   - "remove": delete the chapter titled Title.
+//   - "rename": change the chapter titled Title's title to RenameTo.
+//   - "move": relocate the chapter titled Title to just after the chapter
+//     titled After (or to the front of its arc if After is empty).
+type ChapterOverride struct {
+	Op       string `yaml:"op"`
+	Title    string `yaml:"title"`
+	Url      string `yaml:"url,omitempty"`
+	After    string `yaml:"after,omitempty"`
+	RenameTo string `yaml:"rename_to,omitempty"`
+}
+
+// Load parses the built-in overrides and, if path exists, layers the
+// overrides file at path on top of them. path may be YAML or JSON, since
+// JSON is valid YAML. A missing file is not an error: the built-ins are
+// returned on their own.
+func Load(path string) (*Config, error) {
+	cfg, err := parse(builtinYAML)
+	if err != nil {
+		return nil, fmt.Errorf("parsing built-in overrides: %w", err)
+	}
+
+	user, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	userCfg, err := parse(user)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	cfg.merge(userCfg)
+	return cfg, nil
+}
+
+func parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// merge layers other on top of c: other's regex wins where set, and
+// other's chapter edits run after c's.
+func (c *Config) merge(other *Config) {
+	if c.Serials == nil {
+		c.Serials = map[string]SerialOverrides{}
+	}
+	for name, so := range other.Serials {
+		base := c.Serials[name]
+		if so.ArcIdentifierRegex != "" {
+			base.ArcIdentifierRegex = so.ArcIdentifierRegex
+		}
+		base.Chapters = append(base.Chapters, so.Chapters...)
+		c.Serials[name] = base
+	}
+}
+
+// For returns the overrides for the named serial, or the zero value if it
+// has none.
+func (c *Config) For(name string) SerialOverrides {
+	if c == nil {
+		return SerialOverrides{}
+	}
+	return c.Serials[name]
+}
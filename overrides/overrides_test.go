@@ -0,0 +1,69 @@
+package overrides
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigMerge(t *testing.T) {
+	c := &Config{Serials: map[string]SerialOverrides{
+		"worm": {
+			ArcIdentifierRegex: "base-regex",
+			Chapters:           []ChapterOverride{{Op: "remove", Title: "1.1"}},
+		},
+	}}
+
+	other := &Config{Serials: map[string]SerialOverrides{
+		"worm": {
+			ArcIdentifierRegex: "user-regex",
+			Chapters:           []ChapterOverride{{Op: "rename", Title: "1.2", RenameTo: "1.renamed"}},
+		},
+		"pact": {
+			Chapters: []ChapterOverride{{Op: "remove", Title: "2.1"}},
+		},
+	}}
+
+	c.merge(other)
+
+	worm := c.Serials["worm"]
+	if worm.ArcIdentifierRegex != "user-regex" {
+		t.Errorf("ArcIdentifierRegex = %q, want %q (other's regex should win)", worm.ArcIdentifierRegex, "user-regex")
+	}
+	wantChapters := []ChapterOverride{
+		{Op: "remove", Title: "1.1"},
+		{Op: "rename", Title: "1.2", RenameTo: "1.renamed"},
+	}
+	if !reflect.DeepEqual(worm.Chapters, wantChapters) {
+		t.Errorf("worm.Chapters = %+v, want %+v (base's edits should run before other's)", worm.Chapters, wantChapters)
+	}
+
+	pact := c.Serials["pact"]
+	if len(pact.Chapters) != 1 || pact.Chapters[0].Title != "2.1" {
+		t.Errorf("pact.Chapters = %+v, want a single 2.1 removal", pact.Chapters)
+	}
+}
+
+func TestConfigMergeIntoNilSerials(t *testing.T) {
+	c := &Config{}
+	other := &Config{Serials: map[string]SerialOverrides{
+		"worm": {ArcIdentifierRegex: "regex"},
+	}}
+
+	c.merge(other)
+
+	if c.Serials["worm"].ArcIdentifierRegex != "regex" {
+		t.Errorf("merge into a Config with nil Serials didn't pick up other's entries")
+	}
+}
+
+func TestConfigFor(t *testing.T) {
+	var nilCfg *Config
+	if got := nilCfg.For("worm"); !reflect.DeepEqual(got, SerialOverrides{}) {
+		t.Errorf("(*Config)(nil).For(...) = %+v, want zero value", got)
+	}
+
+	cfg := &Config{Serials: map[string]SerialOverrides{"worm": {ArcIdentifierRegex: "x"}}}
+	if got := cfg.For("pact"); !reflect.DeepEqual(got, SerialOverrides{}) {
+		t.Errorf("For(unknown serial) = %+v, want zero value", got)
+	}
+}
@@ -0,0 +1,122 @@
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/Vanathor/worm_scraper/cache"
+)
+
+// maxBackoff caps the exponential retry delay between fetch attempts.
+const maxBackoff = 30 * time.Second
+
+// Parse fetches a chapter (from the cache if unchanged since the last run)
+// and populates ch using site's selectors, retrying with exponential
+// backoff up to maxRetries times on failure.
+func (ch *Chapter) Parse(site Serial, store *cache.Store, refresh bool, maxRetries int) error {
+	// Get the chapter
+	if strings.HasPrefix(ch.Url, "http") == false {
+		// Make sure it begins with http so we have a fetchable URL
+		ch.Url = "https://" + ch.Url
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoffDelay(attempt))
+		}
+
+		if err := ch.parseOnce(site, store, refresh); err != nil {
+			lastErr = err
+			ch.Retries = attempt + 1
+			continue
+		}
+		return nil
+	}
+
+	return fmt.Errorf("chapter url '%s' failed after %d attempts: %w", ch.Url, maxRetries+1, lastErr)
+}
+
+// parseOnce does a single fetch-and-parse attempt with no retrying.
+func (ch *Chapter) parseOnce(site Serial, store *cache.Store, refresh bool) error {
+	body, err := fetchChapter(ch.Url, store, refresh)
+	if err != nil {
+		return err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	title, date, tags, blocks := site.ParseChapter(doc)
+	ch.Title = title
+	ch.DatePosted = date
+	ch.Tags = tags
+	ch.Blocks = blocks
+	store.SetDatePosted(ch.Url, date)
+
+	return nil
+}
+
+// backoffDelay returns the delay before a retry attempt, following
+// 1s, 2s, 4s, ... capped at maxBackoff, plus up to 50% jitter so a pool of
+// workers retrying together doesn't hammer the host in lockstep.
+func backoffDelay(attempt int) time.Duration {
+	delay := time.Second * time.Duration(uint64(1)<<uint(attempt-1))
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
+
+// fetchChapter returns the HTML body for url, preferring the cache: if we
+// have a cached copy and refresh is false, it's revalidated with a
+// conditional GET so unchanged chapters cost only a 304.
+func fetchChapter(url string, store *cache.Store, refresh bool) (string, error) {
+	entry, cachedBody, hasCache := store.Entry(url)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCache && !refresh {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasCache {
+		return cachedBody, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("fetching '" + url + "' returned status " + resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := store.Save(url, string(body), resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), ""); err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
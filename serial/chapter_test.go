@@ -0,0 +1,30 @@
+package serial
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempt   int
+		baseDelay time.Duration // delay before the up-to-50% jitter is added
+	}{
+		{name: "first retry is one second", attempt: 1, baseDelay: time.Second},
+		{name: "second retry doubles", attempt: 2, baseDelay: 2 * time.Second},
+		{name: "fifth retry", attempt: 5, baseDelay: 16 * time.Second},
+		{name: "caps at maxBackoff", attempt: 10, baseDelay: maxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := backoffDelay(tt.attempt)
+				if got < tt.baseDelay || got > tt.baseDelay+tt.baseDelay/2 {
+					t.Fatalf("backoffDelay(%d) = %v, want between %v and %v", tt.attempt, got, tt.baseDelay, tt.baseDelay+tt.baseDelay/2)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package serial defines the scraped-content model shared by every
+// supported Wildbow web serial, plus the Serial interface each site's
+// backend implements.
+package serial
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/Vanathor/worm_scraper/content"
+)
+
+// Arc groups Chapters under a titled section of a serial's table of
+// contents (e.g. "Arc 1: Gestation" or "Epilogue").
+type Arc struct {
+	Identifier string
+	Title      string
+	Chapters   []Chapter
+}
+
+// Chapter is a single chapter, before or after it has been fetched.
+type Chapter struct {
+	Title      string
+	Url        string
+	Tags       []string
+	Blocks     []content.Block
+	Retries    int
+	DatePosted string
+}
+
+// WhichArc returns the Arc that ch belongs to, matched by the arc
+// identifier prefix of its title (e.g. "3.2" belongs to arc "3").
+func (ch *Chapter) WhichArc(arcList []*Arc) (*Arc, error) {
+	for _, arc := range arcList {
+		if strings.Replace(ch.Title[:2], ".", "", -1) == arc.Identifier {
+			return arc, nil
+		}
+	}
+	return &Arc{}, errors.New("chapter '" + ch.Title + "' did not match any Arcs")
+}
+
+// Serial is a Wildbow web serial that can be scraped: something that knows
+// where its table of contents lives and how to pull structure and content
+// out of its WordPress theme's HTML.
+type Serial interface {
+	// Name is the identifier used to select this serial with --serial.
+	Name() string
+	// BaseURL is the serial's homepage.
+	BaseURL() string
+	// TableOfContentsURL is where ParseTOC's input document comes from.
+	TableOfContentsURL() string
+	// ParseTOC extracts the Arc/Chapter structure from the table of
+	// contents page, without fetching the chapters themselves.
+	ParseTOC(doc *goquery.Document) []*Arc
+	// ParseChapter extracts a chapter's content from its own page.
+	ParseChapter(doc *goquery.Document) (title, date string, tags []string, blocks []content.Block)
+	// SetArcIdentifierRegex overrides the regex used to pull an arc's
+	// identifier out of its heading line (see the overrides package).
+	SetArcIdentifierRegex(re *regexp.Regexp)
+}
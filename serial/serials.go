@@ -0,0 +1,70 @@
+package serial
+
+import "fmt"
+
+var (
+	// Worm is the original serial, at parahumans.wordpress.com. Its table
+	// of contents is missing a link to chapter E.2; that's patched in via
+	// the overrides package's built-in config rather than code here.
+	Worm Serial = &wordpressSerial{wordpressConfig: wordpressConfig{
+		name:            "worm",
+		baseURL:         "https://parahumans.wordpress.com/",
+		tocURL:          "https://parahumans.wordpress.com/table-of-contents/",
+		arcKeyword:      "Arc",
+		epilogueKeyword: "Epilogue",
+	}}
+
+	// Pact is Wildbow's second serial, at pactwebserial.wordpress.com.
+	Pact Serial = &wordpressSerial{wordpressConfig: wordpressConfig{
+		name:            "pact",
+		baseURL:         "https://pactwebserial.wordpress.com/",
+		tocURL:          "https://pactwebserial.wordpress.com/table-of-contents/",
+		arcKeyword:      "Arc",
+		epilogueKeyword: "Interlude",
+	}}
+
+	// Twig is Wildbow's third serial, at twigserial.wordpress.com.
+	Twig Serial = &wordpressSerial{wordpressConfig: wordpressConfig{
+		name:            "twig",
+		baseURL:         "https://twigserial.wordpress.com/",
+		tocURL:          "https://twigserial.wordpress.com/table-of-contents/",
+		arcKeyword:      "Arc",
+		epilogueKeyword: "Interlude",
+	}}
+
+	// Ward is Worm's sequel, at parahumans.net.
+	Ward Serial = &wordpressSerial{wordpressConfig: wordpressConfig{
+		name:            "ward",
+		baseURL:         "https://www.parahumans.net/",
+		tocURL:          "https://www.parahumans.net/table-of-contents/",
+		arcKeyword:      "Arc",
+		epilogueKeyword: "Epilogue",
+	}}
+
+	// Pale is Wildbow's fifth serial, at pale.wordpress.com.
+	Pale Serial = &wordpressSerial{wordpressConfig: wordpressConfig{
+		name:            "pale",
+		baseURL:         "https://pale.wordpress.com/",
+		tocURL:          "https://pale.wordpress.com/table-of-contents/",
+		arcKeyword:      "Arc",
+		epilogueKeyword: "Interlude",
+	}}
+)
+
+// byName indexes the built-in serials for Lookup.
+var byName = map[string]Serial{
+	"worm": Worm,
+	"pact": Pact,
+	"twig": Twig,
+	"ward": Ward,
+	"pale": Pale,
+}
+
+// Lookup returns the built-in Serial registered under name.
+func Lookup(name string) (Serial, error) {
+	s, ok := byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown serial %q (expected one of worm, pact, twig, ward, pale)", name)
+	}
+	return s, nil
+}
@@ -0,0 +1,118 @@
+package serial
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/Vanathor/worm_scraper/content"
+)
+
+// wordpressConfig is what differs between Wildbow's WordPress-hosted
+// serials: their URLs and the heading keywords their TOC page uses to mark
+// off arcs.
+type wordpressConfig struct {
+	name            string
+	baseURL         string
+	tocURL          string
+	arcKeyword      string
+	epilogueKeyword string
+}
+
+// wordpressSerial implements Serial for a WordPress-hosted Wildbow serial.
+// All of them use the same theme and near-identical markup, so one
+// parametrized implementation covers Worm, Pact, Twig, Ward, and Pale.
+type wordpressSerial struct {
+	wordpressConfig
+
+	// arcIdentifierRegex overrides arcNumberRe when set, via
+	// SetArcIdentifierRegex.
+	arcIdentifierRegex *regexp.Regexp
+}
+
+func (s *wordpressSerial) Name() string               { return s.name }
+func (s *wordpressSerial) BaseURL() string            { return s.baseURL }
+func (s *wordpressSerial) TableOfContentsURL() string { return s.tocURL }
+
+// SetArcIdentifierRegex overrides the regex used to pull an arc's
+// identifier out of its heading line.
+func (s *wordpressSerial) SetArcIdentifierRegex(re *regexp.Regexp) {
+	s.arcIdentifierRegex = re
+}
+
+func (s *wordpressSerial) identifierRegex() *regexp.Regexp {
+	if s.arcIdentifierRegex != nil {
+		return s.arcIdentifierRegex
+	}
+	return arcNumberRe
+}
+
+var arcNumberRe = regexp.MustCompile(`[0-9]+`)
+
+// ParseTOC walks the table of contents page: arc headings come from the
+// plain text of the page, while chapter links are every non-share-icon
+// link inside the entry content, assigned to the arc their title prefix
+// matches.
+func (s *wordpressSerial) ParseTOC(doc *goquery.Document) []*Arc {
+	arcs := s.parseArcHeadings(doc.Find(".entry-content").Text())
+
+	doc.Find(".entry-content a:not([class*=share-icon])").Each(func(_ int, sel *goquery.Selection) {
+		ch := Chapter{}
+		ch.Title = strings.Replace(strings.TrimSpace(sel.Text()), "\n", "", -1)
+		ch.Url, _ = sel.Attr("href")
+
+		if ch.Title == "" {
+			return
+		}
+
+		arc, _ := ch.WhichArc(arcs)
+		arc.Chapters = append(arc.Chapters, ch)
+	})
+
+	return arcs
+}
+
+func (s *wordpressSerial) parseArcHeadings(text string) []*Arc {
+	arcs := []*Arc{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, s.arcKeyword) {
+			arcs = append(arcs, &Arc{
+				Identifier: s.identifierRegex().FindString(line),
+				Title:      line,
+			})
+		} else if strings.HasPrefix(line, s.epilogueKeyword) {
+			// Worm's lone "Epilogue" heading has no number to extract, so
+			// it keeps the literal "E" identifier; Pact/Twig/Ward/Pale all
+			// have many "Interlude N" headings scattered through their TOC,
+			// so each needs its own identifier or WhichArc's first-match
+			// lookup would bucket every interlude's chapters into whichever
+			// one was seen first.
+			id := s.identifierRegex().FindString(line)
+			if id == "" {
+				id = "E"
+			}
+			arcs = append(arcs, &Arc{
+				Identifier: id,
+				Title:      line,
+			})
+		}
+	}
+	return arcs
+}
+
+// ParseChapter extracts a chapter's title, tags, posted date, and content
+// blocks from its page.
+func (s *wordpressSerial) ParseChapter(doc *goquery.Document) (title, date string, tags []string, blocks []content.Block) {
+	title = doc.Find("h1.entry-title").Text()
+	date = doc.Find("time.entry-date").Text()
+
+	doc.Find(".entry-meta a[rel=tag]").Each(func(_ int, sel *goquery.Selection) {
+		tags = append(tags, sel.Text())
+	})
+
+	blocks = content.BuildBlocks(doc.Find(".entry-content"))
+
+	return title, date, tags, blocks
+}
@@ -1,294 +1,258 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
 	"strings"
+	"sync"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/codegangsta/cli"
-	"github.com/puerkitobio/goquery"
+	"github.com/vbauerster/mpb/v8"
+	"github.com/vbauerster/mpb/v8/decor"
+
+	"github.com/Vanathor/worm_scraper/cache"
+	"github.com/Vanathor/worm_scraper/content"
+	"github.com/Vanathor/worm_scraper/ebook"
+	"github.com/Vanathor/worm_scraper/overrides"
+	"github.com/Vanathor/worm_scraper/serial"
 )
 
-const (
-	MainSite        = "https://parahumans.wordpress.com/"
-	TableOfContents = "https://parahumans.wordpress.com/table-of-contents/"
-)
-
-type Arc struct {
-	Identifier string
-	Title      string
-	Chapters   []Chapter
-}
-
-type Chapter struct {
-	Title      string
-	Url        string
-	Tags       []string
-	Paragraphs []Paragraph
-	Retries    int
-	DatePosted string
-}
-
-type Paragraph string
-
-// Format the paragraph
-func (p *Paragraph) Format() {
-	s := string(*p)
-
-	// Handle emphasis
-	s = strings.Replace(s, "<em>", "*", -1)
-	s = strings.Replace(s, "</em>", "*", -1)
-	s = strings.Replace(s, "<i>", "*", -1)
-	s = strings.Replace(s, "</i>", "*", -1)
-
-	// Handle bold
-	s = strings.Replace(s, "<strong>", "**", -1)
-	s = strings.Replace(s, "</strong>", "**", -1)
-	s = strings.Replace(s, "<b>", "**", -1)
-	s = strings.Replace(s, "</b>", "**", -1)
-
-	// Remove new lines
-	s = strings.Replace(s, "\n", "", -1)
-
-	// And random double spaces
-	s = strings.Replace(s, ".  ", ". ", -1)
-
-	*p = Paragraph(s)
-}
-
-// Return the Arc that the given chapter belongs to
-func (ch *Chapter) WhichArc(arcList []*Arc) (*Arc, error) {
-	for _, arc := range arcList {
-		if strings.Replace(ch.Title[:2], ".", "", -1) == arc.Identifier {
-			return arc, nil
+// CacheDir is where fetched chapter HTML and its ETag/Last-Modified index
+// are kept between runs.
+const CacheDir = ".worm_cache"
+
+// toEbookArcs converts the scraped Arc model into the plain data structs
+// the ebook package renders from.
+func toEbookArcs(arcs []*serial.Arc) []ebook.Arc {
+	out := make([]ebook.Arc, len(arcs))
+	for i, arc := range arcs {
+		out[i] = ebook.Arc{
+			Identifier: arc.Identifier,
+			Title:      arc.Title,
+		}
+		for _, chapter := range arc.Chapters {
+			out[i].Chapters = append(out[i].Chapters, ebook.Chapter{
+				Title:      chapter.Title,
+				Url:        chapter.Url,
+				Tags:       chapter.Tags,
+				DatePosted: chapter.DatePosted,
+				Blocks:     chapter.Blocks,
+			})
 		}
 	}
-	return &Arc{}, errors.New("chapter '" + ch.Title + "' did not match any Arcs")
+	return out
 }
 
-// Parse a chapter and return it
-func (ch *Chapter) Parse(done chan bool) {
-	if ch.Retries > 3 {
-		panic("Chapter url '" + ch.Url + "' has timed out too many times")
-	}
-	// Get the chapter
-	if strings.HasPrefix(ch.Url, "http") == false {
-		// Make sure it begins with http so goquery can use it
-		ch.Url = "https://" + ch.Url
-	}
-	doc, err := goquery.NewDocument(ch.Url)
+// writeMarkdown writes the arcs out as a single Markdown file, the same
+// format worm_scraper has always produced for --format md.
+func writeMarkdown(meta ebook.Metadata, arcs []*serial.Arc, opts ebook.Options, path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
 	if err != nil {
-		// Try again
-		ch.Retries++
-		go ch.Parse(done)
-		return
+		return err
 	}
+	defer f.Close()
+
+	f.WriteString("# " + meta.Title + "\n\n")
+	f.WriteString("By " + meta.Author + "\n\n")
+	f.WriteString(meta.Description)
+
+	for _, arc := range arcs {
+		f.WriteString("\n\n" + arc.Title)
+		for _, chapter := range arc.Chapters {
+			f.WriteString("\n\n")
+			f.WriteString("## " + chapter.Title + "\n\n")
+			if opts.WithTags {
+				f.WriteString("**Tags:** " + strings.Join(chapter.Tags, ", ") + "  ")
+			}
+			if opts.WithDate {
+				f.WriteString("**Date:** " + chapter.DatePosted + "  ")
+			}
+			if opts.WithLink {
+				f.WriteString("**Link:** " + chapter.Url + "  ")
+			}
+			f.WriteString("\n\n")
 
-	// Set the new chapter title
-	ch.Title = doc.Find("h1.entry-title").Text()
-
-	// Set the tags
-	doc.Find(".entry-meta a[rel=tag]").Each(func(_ int, s *goquery.Selection) {
-		ch.Tags = append(ch.Tags, s.Text())
-	})
-
-	// Get the date it was posted
-	ch.DatePosted = doc.Find("time.entry-date").Text()
-
-	// Now we'll get all the paragraphs
-	doc.Find(".entry-content > p").Each(func(_ int, s *goquery.Selection) {
-		// Check for the previous/next links
-		if len(s.Find("a").Nodes) > 0 {
-			return
-		}
-
-		// Get the paragraph HTML
-		st, _ := s.Html()
-		para := Paragraph("")
-
-		// Get the actual paragraph
-		if val, exists := s.Attr("padding-left"); exists && val == "30px" {
-			// Check to see if the paragraph is special (indented) block
-			para = Paragraph("    " + st)
-		} else if val, exists := s.Attr("text-align"); exists && val == "center" {
-			// Otherwise check to see if it's a separator paragraph
-			para = Paragraph("----------")
-		} else {
-			// It's just a normal paragraph in this case
-			para = Paragraph(st)
+			f.WriteString(content.RenderMarkdown(chapter.Blocks))
+			f.WriteString("\n\n")
 		}
-
-		// And add the paragraph to the chapter
-		para.Format()
-		ch.Paragraphs = append(ch.Paragraphs, para)
-	})
-
-	// Finally, let's signal a success
-	done <- true
+	}
+	return nil
 }
 
-// Return a slice of Arcs extracted from the table of contents
-func ParseArcs(s string) []*Arc {
-	arcs := []*Arc{}
-	r, _ := regexp.Compile(`[0-9]+`)
-	for _, line := range strings.Split(s, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Arc") {
-			arcs = append(arcs, &Arc{
-				Identifier: r.FindString(line),
-				Title:      line,
-			})
-		} else if strings.HasPrefix(line, "Epilogue") {
-			arcs = append(arcs, &Arc{
-				Identifier: "E",
-				Title:      line,
-			})
-		}
+// writePDF writes an intermediate Markdown file and shells out to Pandoc to
+// convert it to a PDF, since we don't have a native PDF writer. Unlike
+// --format epub/mobi, this still requires Pandoc to be installed.
+func writePDF(meta ebook.Metadata, arcs []*serial.Arc, opts ebook.Options) error {
+	md := meta.Title + ".md"
+	if err := writeMarkdown(meta, arcs, opts, md); err != nil {
+		return err
+	}
+
+	fmt.Print("Attempting to convert Markdown file to PDF via Pandoc... ")
+	cmd := exec.Command("pandoc", md, "-o", meta.Title+".pdf")
+	if err := cmd.Run(); err != nil {
+		fmt.Println("Conversion failed! Make sure you've installed Pandoc (http://johnmacfarlane.net/pandoc/installing.html) if you want PDF output. In the meantime, we've left you the Markdown file.")
+		return nil
 	}
-	return arcs
+	return os.Remove(md)
 }
 
 func main() {
 	// Define the app
 	app := cli.NewApp()
 	app.Name = "Worm Scraper"
-	app.Usage = "A tool to let you get an updated EPUB copy of the serial web novel Worm, by Wildbow"
+	app.Usage = "A tool to let you get an updated EPUB copy of Wildbow's web serials"
 	app.Version = "1.0"
 	app.Author = "Benjamin Harris"
 
 	// Define the application flags
 	app.Flags = []cli.Flag{
-		cli.BoolFlag{"pdf", "Save the book as a PDF instead of an EPUB, if possible"},
-		cli.BoolFlag{"with-link", "Include a link to the chapter online"},
-		cli.BoolFlag{"with-tags", "Include the tags each chapter was posted under"},
-		cli.BoolFlag{"with-date", "Include the date each chapter was posted"},
+		cli.StringFlag{Name: "serial", Value: "worm", Usage: "Which serial to scrape: worm, pact, twig, ward, or pale"},
+		cli.StringFlag{Name: "format", Value: "epub", Usage: "Output format: epub, mobi, md, or pdf"},
+		cli.BoolFlag{Name: "with-link", Usage: "Include a link to the chapter online"},
+		cli.BoolFlag{Name: "with-tags", Usage: "Include the tags each chapter was posted under"},
+		cli.BoolFlag{Name: "with-date", Usage: "Include the date each chapter was posted"},
+		cli.BoolFlag{Name: "refresh", Usage: "Ignore the on-disk cache and re-download every chapter"},
+		cli.IntFlag{Name: "concurrency", Value: 8, Usage: "Number of chapters to fetch at once"},
+		cli.IntFlag{Name: "max-retries", Value: 3, Usage: "Maximum retries per chapter before giving up"},
+		cli.StringFlag{Name: "overrides", Value: "overrides.yaml", Usage: "Path to a TOC overrides file (YAML or JSON); missing is fine"},
 	}
 
 	// The heart of the application
 	app.Action = func(context *cli.Context) {
-		// Starting the program
-		fmt.Println("Starting to scrape Worm")
+		format := context.String("format")
+		switch format {
+		case "epub", "mobi", "md", "pdf":
+		default:
+			panic("unknown --format '" + format + "', expected epub, mobi, md, or pdf")
+		}
 
-		// Get the list of arcs from the table of contents
-		fmt.Println("Gathering links from table of contents...")
-		contents, err := goquery.NewDocument(TableOfContents)
+		site, err := serial.Lookup(context.String("serial"))
 		if err != nil {
-			panic("Failed to get the table of contents! " + err.Error())
+			panic(err)
 		}
 
-		// Parse the arcs
-		arcs := ParseArcs(contents.Find(".entry-content").Text())
-
-		// Now get the links for the arc chapters
-		contents.Find(".entry-content a:not([class*=share-icon])").Each(func(_ int, s *goquery.Selection) {
-			ch := Chapter{}
-			ch.Title = strings.Replace(strings.TrimSpace(s.Text()), "\n", "", -1)
-			ch.Url, _ = s.Attr("href")
-
-			if ch.Title == "" {
-				return
-			}
+		fmt.Println("Starting to scrape", site.Name())
 
-			arc, _ := ch.WhichArc(arcs)
-			arc.Chapters = append(arc.Chapters, ch)
-		})
-
-		// Manually add missing chapter in Epilogue
-		c := Chapter{
-			Title: "E.2",
-			Url:   "https://parahumans.wordpress.com/2013/11/05/teneral-e-2/",
+		store, err := cache.Open(CacheDir)
+		if err != nil {
+			panic("Failed to open cache at '" + CacheDir + "': " + err.Error())
 		}
-		a, _ := c.WhichArc(arcs)
-		a.Chapters = append(a.Chapters, c)
-		copy(a.Chapters[1+1:], a.Chapters[1:])
-		a.Chapters[1] = c
+		refresh := context.Bool("refresh")
 
-		// Now start getting the chapters
-		chapters := 0
-		done := make(chan bool)
-		for _, arc := range arcs {
-			for i, _ := range arc.Chapters {
-				chapters++
-				go arc.Chapters[i].Parse(done)
-			}
+		// Load TOC overrides (the built-ins always apply; the file at
+		// --overrides layers on top if it exists) and apply anything
+		// that affects parsing itself before we fetch the TOC.
+		overrideCfg, err := overrides.Load(context.String("overrides"))
+		if err != nil {
+			panic("Failed to load overrides: " + err.Error())
 		}
-
-		fmt.Println("Starting to parse", chapters, "chapters")
-		fmt.Print("Finished: ")
-
-		totalChapters := chapters
-		for {
-			select {
-			case <-done:
-				chapters--
-				fmt.Print(totalChapters-chapters, ",")
-			}
-			if chapters == 0 {
-				// We're done with all the chapters
-				close(done)
-				fmt.Println()
-				break
+		siteOverrides := overrideCfg.For(site.Name())
+		if siteOverrides.ArcIdentifierRegex != "" {
+			re, err := regexp.Compile(siteOverrides.ArcIdentifierRegex)
+			if err != nil {
+				panic("Bad arc_identifier_regex for '" + site.Name() + "': " + err.Error())
 			}
+			site.SetArcIdentifierRegex(re)
 		}
 
-		// And let's write all this stuff to a file now
-		fmt.Println("Saving results to file...")
-		f, err := os.OpenFile("Worm.md", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		// Get the list of arcs from the table of contents
+		fmt.Println("Gathering links from table of contents...")
+		contents, err := goquery.NewDocument(site.TableOfContentsURL())
 		if err != nil {
-			panic(err)
+			panic("Failed to get the table of contents! " + err.Error())
 		}
-		defer f.Close()
 
-		// Define pagebreak
-		PageBreak := "\n\n"
+		// Parse the table of contents into arcs and chapter links, then
+		// apply any insert/remove/rename/reorder overrides.
+		arcs := overrides.Apply(site.ParseTOC(contents), siteOverrides)
 
-		// Write the cover
-		f.WriteString("# Worm\n\n")
-		f.WriteString("By Wildbow\n\n")
-		f.WriteString("Website: " + MainSite)
+		// Queue up every chapter for the worker pool
+		jobs := make(chan *serial.Chapter)
+		go func() {
+			for _, arc := range arcs {
+				for i := range arc.Chapters {
+					jobs <- &arc.Chapters[i]
+				}
+			}
+			close(jobs)
+		}()
 
-		// Now loop through the Arcs
+		totalChapters := 0
 		for _, arc := range arcs {
-			f.WriteString(PageBreak + arc.Title)
-			for _, chapter := range arc.Chapters {
-				f.WriteString("\n\n")
-				f.WriteString("## " + chapter.Title + "\n\n")
-				if context.Bool("with-tags") {
-					f.WriteString("**Tags:** " + strings.Join(chapter.Tags, ", ") + "  ")
-				}
-				if context.Bool("with-date") {
-					f.WriteString("**Date:** " + chapter.DatePosted + "  ")
-				}
-				if context.Bool("with-link") {
-					f.WriteString("**Link:** " + chapter.Url + "  ")
+			totalChapters += len(arc.Chapters)
+		}
+		fmt.Println("Starting to parse", totalChapters, "chapters")
+
+		maxRetries := context.Int("max-retries")
+		concurrency := context.Int("concurrency")
+
+		var wg sync.WaitGroup
+		progress := mpb.New(mpb.WithWaitGroup(&wg))
+		bar := progress.New(int64(totalChapters),
+			mpb.BarStyle(),
+			mpb.PrependDecorators(decor.Name("Chapters")),
+			mpb.AppendDecorators(decor.CountersNoUnit("%d / %d"), decor.Percentage()),
+		)
+
+		var failuresMu sync.Mutex
+		var failures []error
+
+		for w := 0; w < concurrency; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ch := range jobs {
+					if err := ch.Parse(site, store, refresh, maxRetries); err != nil {
+						failuresMu.Lock()
+						failures = append(failures, err)
+						failuresMu.Unlock()
+					}
+					bar.Increment()
 				}
-				f.WriteString("\n\n")
+			}()
+		}
+		progress.Wait()
 
-				// Now save the chapter's paragraphs
-				for _, p := range chapter.Paragraphs {
-					f.WriteString(string(p) + "\n\n")
-				}
+		if len(failures) > 0 {
+			fmt.Println(len(failures), "chapter(s) failed to download:")
+			for _, err := range failures {
+				fmt.Println(" -", err)
 			}
 		}
 
-		// Now let's try to convert the markdown file into an ebook format (epub, pdf)
-		fmt.Print("Attempting to convert Markdown file... ")
-		cmdText := []string{"-S", "Worm.md", "--epub-chapter-level", "2", "-o", "Worm.epub"}
-		if context.Bool("pdf") {
-			cmdText = []string{"Worm.md", "-o", "Worm.pdf"}
-			PageBreak = `<div style="page-break-after: always;"></div>`
+		// Now save the results in whichever format was asked for
+		opts := ebook.Options{
+			WithLink: context.Bool("with-link"),
+			WithTags: context.Bool("with-tags"),
+			WithDate: context.Bool("with-date"),
+		}
+		meta := ebook.Metadata{
+			Title:       strings.Title(site.Name()),
+			Author:      "Wildbow",
+			Description: "Website: " + site.BaseURL(),
+		}
+
+		fmt.Println("Saving results to file...")
+		switch format {
+		case "epub":
+			err = ebook.WriteEPUB(meta, toEbookArcs(arcs), opts, meta.Title+".epub")
+		case "mobi":
+			err = ebook.WriteMOBI(meta, toEbookArcs(arcs), meta.Title+".mobi")
+		case "md":
+			err = writeMarkdown(meta, arcs, opts, meta.Title+".md")
+		case "pdf":
+			err = writePDF(meta, arcs, opts)
+		default:
+			panic("unknown --format '" + format + "', expected epub, mobi, md, or pdf")
 		}
-		cmd := exec.Command("pandoc", cmdText...)
-		err = cmd.Run()
 		if err != nil {
-			fmt.Println("Conversion failed! Make sure you've installed Pandoc (http://johnmacfarlane.net/pandoc/installing.html) if you want to convert the generated Markdown file to an ebook compatible format. In the meantime, we've left you the Markdown file.")
-		} else {
-			_ = os.Remove("Worm.md")
-			fmt.Println("Completed!")
+			panic(err)
 		}
+		fmt.Println("Completed!")
 	}
 
 	// Run the application